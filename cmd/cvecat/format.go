@@ -0,0 +1,134 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"os"
+	"regexp"
+	"strings"
+	htext "text/template"
+
+	"go.iscode.ca/cvecat/pkg/cvecat"
+	"go.iscode.ca/cvecat/pkg/cvss"
+	"go.iscode.ca/cvecat/pkg/report"
+)
+
+func join(elems []string, sep string) string {
+	return strings.Join(elems, sep)
+}
+
+var markdownEscaper = strings.NewReplacer(
+	// The backslash must be first.
+	`\`, `\\`,
+	`*`, `\*`,
+	`_`, `\_`,
+	`#`, `\#`,
+	"`", "\\`",
+	`[`, `\[`,
+	`]`, `\]`,
+	`(`, `\(`,
+	`)`, `\)`,
+	`>`, `\>`,
+	`+`, `\+`,
+	`-`, `\-`,
+	`.`, `\.`,
+	`!`, `\!`,
+	`|`, `\|`,
+	`~`, `\~`,
+)
+
+// mdescape takes a string and escapes special markdown characters.
+// Allows embedding text within a markdown document without its contents
+// being interpreted as markdown syntax.
+func mdescape(text string) string {
+	return markdownEscaper.Replace(text)
+}
+
+// funcMap is shared by both the text/template and html/template engines so
+// a report can call the same functions whichever engine its name resolves
+// to; html/template additionally escapes the output.
+func funcMap() htext.FuncMap {
+	return htext.FuncMap{
+		"replace": func(s, expr, repl string) string {
+			re, err := regexp.Compile(expr)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "error: %v", err)
+				return s
+			}
+			return re.ReplaceAllString(s, repl)
+		},
+		"join":     join,
+		"mdescape": mdescape,
+		"cvss": func(data *cvecat.Data) cvss.Score {
+			score, _ := data.BestScore()
+			return score
+		},
+		"severity": func(data *cvecat.Data) string {
+			score, ok := data.BestScore()
+			if !ok {
+				return ""
+			}
+			return score.BaseSeverity
+		},
+		"vector": func(data *cvecat.Data) string {
+			score, ok := data.BestScore()
+			if !ok {
+				return ""
+			}
+			return score.Vector
+		},
+		"wrap":          report.Wrap,
+		"colorSeverity": report.ColorSeverity,
+		"groupByVendor": report.GroupByVendor,
+	}
+}
+
+// render resolves argv.format to a template source and executes it against
+// data. The name is resolved in order: a file in -format-file, a built-in
+// report.Lookup name, then argv.format itself taken as a literal template.
+// report.HTML, however resolved, is parsed with html/template so its
+// escaping rules apply; every other name uses text/template.
+func (argv *argvT) render(data any) ([]byte, error) {
+	name := argv.format
+	src := argv.format
+
+	if t, ok, err := report.FileOverride(argv.formatFile, name); err != nil {
+		return []byte{}, err
+	} else if ok {
+		src = t
+	} else if t, ok := report.Lookup(name); ok {
+		src = t
+	}
+
+	if name == report.HTML {
+		return formatHTML(src, data)
+	}
+	return format(src, data)
+}
+
+func format(src string, data any) ([]byte, error) {
+	tmpl, err := htext.New("format").Funcs(funcMap()).Parse(src)
+	if err != nil {
+		return []byte{}, err
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return buf.Bytes(), err
+	}
+	return buf.Bytes(), nil
+}
+
+func formatHTML(src string, data any) ([]byte, error) {
+	tmpl, err := template.New("format").Funcs(template.FuncMap(funcMap())).Parse(src)
+	if err != nil {
+		return []byte{}, err
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return buf.Bytes(), err
+	}
+	return buf.Bytes(), nil
+}
@@ -1,8 +1,6 @@
 package main
 
 import (
-	"bufio"
-	"bytes"
 	"encoding/json"
 	"errors"
 	"flag"
@@ -11,31 +9,41 @@ import (
 	"net/http"
 	"os"
 	"path"
-	"regexp"
-	"strconv"
+	"path/filepath"
+	"runtime"
 	"strings"
-	"text/template"
 	"time"
 
 	"go.iscode.ca/cvecat/internal/config"
 	"go.iscode.ca/cvecat/pkg/cve5"
 	"go.iscode.ca/cvecat/pkg/cvecat"
+	"go.iscode.ca/cvecat/pkg/cveid"
+	"go.iscode.ca/cvecat/pkg/cvss"
+	"go.iscode.ca/cvecat/pkg/gitrepo"
+	"go.iscode.ca/cvecat/pkg/mitre"
+	"go.iscode.ca/cvecat/pkg/osv"
 )
 
 type argvT struct {
-	cve     []string
-	format  string
-	dryrun  bool
-	verbose int
+	cve         []string
+	format      string
+	dryrun      bool
+	verbose     int
+	offline     bool
+	mirror      *gitrepo.Repo
+	mitre       *mitre.Client
+	concurrency int
+	retries     int
+	limiter     *tokenLimiter
+	minScore    float64
+	minSeverity string
+	formatFile  string
 }
 
-var (
-	errNoDescr          = errors.New("no description")
-	errInvalidCVE       = errors.New("invalid CVE")
-	errInvalidCVEPrefix = errors.New("invalid CVE prefix")
-	errInvalidCVEYear   = errors.New("invalid CVE year")
-	errInvalidCVEID     = errors.New("invalid CVE identifier")
-)
+// httpClient is shared by read() and can be given a -timeout deadline.
+var httpClient = http.DefaultClient
+
+var errNoDescr = errors.New("no description")
 
 func getenv(k, def string) string {
 	if v, ok := os.LookupEnv(k); ok {
@@ -62,8 +70,31 @@ func args() *argvT {
 			`*{{.CVE.CveMetadata.CveID}}*: {{ replace (index .CVE.Containers.Cna.Descriptions 0).Value "(?m)\n" " " }}
 `,
 		),
-		"Output template",
+		"Output template, or one of the built-in report names: markdown, plain, html, slack, jira",
+	)
+
+	repo := flag.String(
+		"repo",
+		getenv("CVECAT_REPO", ""),
+		fmt.Sprintf("Resolve CVEs from a local clone of %s at PATH, cloning it there on first use if PATH does not exist yet (suggested location: %s)", gitrepo.UpstreamURL, defaultRepoDir()),
 	)
+	update := flag.Bool("update", false, "Fetch and fast-forward -repo before reading")
+	offline := flag.Bool("offline", false, "Forbid network access (requires -repo)")
+
+	key := flag.String("key", getenv("CVE_API_KEY", ""), "MITRE CVE Services API key")
+	user := flag.String("user", getenv("CVE_API_USER", ""), "MITRE CVE Services API user")
+	org := flag.String("org", getenv("CVE_API_ORG", ""), "MITRE CVE Services API org")
+	test := flag.Bool("test", false, "Use the MITRE CVE Services sandbox (cveawg-test.mitre.org)")
+
+	jobs := flag.Int("j", runtime.NumCPU(), "Number of concurrent fetch workers")
+	retries := flag.Int("retries", 3, "Retry attempts for transient fetch errors")
+	timeout := flag.Duration("timeout", 30*time.Second, "HTTP client timeout")
+	rate := flag.Int("rate", 0, "Maximum fetch requests per second (0: unlimited)")
+
+	minScore := flag.Float64("min-score", 0, "Skip records with a CVSS base score below this")
+	minSeverity := flag.String("min-severity", "", "Skip records below this CVSS severity (LOW, MEDIUM, HIGH, CRITICAL)")
+
+	formatFile := flag.String("format-file", "", "Directory of -format template overrides (<name>.tmpl)")
 
 	verbose := flag.Int("verbose", 0, "Enable debug messages")
 	help := flag.Bool("help", false, "Display usage")
@@ -75,46 +106,100 @@ func args() *argvT {
 		os.Exit(1)
 	}
 
-	return &argvT{
-		cve:     flag.Args(),
-		format:  *format,
-		dryrun:  *dryrun,
-		verbose: *verbose,
+	if *retries < 0 {
+		*retries = 0
 	}
+
+	argv := &argvT{
+		cve:         flag.Args(),
+		format:      *format,
+		dryrun:      *dryrun,
+		verbose:     *verbose,
+		offline:     *offline,
+		concurrency: *jobs,
+		retries:     *retries,
+		limiter:     newTokenLimiter(*rate),
+		minScore:    *minScore,
+		minSeverity: *minSeverity,
+		formatFile:  *formatFile,
+	}
+
+	if *timeout > 0 {
+		httpClient = &http.Client{Timeout: *timeout}
+	}
+
+	switch {
+	case *repo != "":
+		mirror, err := gitrepo.Open(*repo, "", *offline)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "error:", err)
+			os.Exit(1)
+		}
+		if *update {
+			if err := mirror.Update(); err != nil {
+				fmt.Fprintln(os.Stderr, "error:", err)
+				os.Exit(1)
+			}
+		}
+		argv.mirror = mirror
+	case *offline:
+		fmt.Fprintln(os.Stderr, "error: -offline requires -repo")
+		os.Exit(1)
+	}
+
+	if *key != "" {
+		argv.mitre = mitre.NewClient(*key, *user, *org, *test, httpClient)
+	}
+
+	return argv
+}
+
+// defaultRepoDir is the clone location suggested in -repo's usage text.
+// It is not used as a default: without -repo, no mirror is opened.
+func defaultRepoDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return filepath.Join(".cache", "cvecat", "cvelistV5")
+	}
+	return filepath.Join(dir, "cvecat", "cvelistV5")
 }
 
 func main() {
 	argv := args()
 
+	if len(argv.cve) > 0 {
+		switch argv.cve[0] {
+		case "list", "reserve", "quota", "update":
+			if err := argv.mitreCommand(argv.cve[0], argv.cve[1:]); err != nil {
+				fmt.Fprintln(os.Stderr, "error:", err)
+				os.Exit(1)
+			}
+			return
+		}
+	}
+
 	var r io.Reader = os.Stdin
 
 	if len(argv.cve) > 0 {
 		r = strings.NewReader(strings.Join(argv.cve, "\n"))
 	}
 
-	scanner := bufio.NewScanner(r)
-	for scanner.Scan() {
-		cve := strings.TrimSpace(scanner.Text())
-		if cve == "" {
-			continue
+	argv.runPipeline(r)
+}
+
+func (argv *argvT) run(cve string) ([]byte, error) {
+	if cve == "-" {
+		if argv.dryrun {
+			return []byte{}, nil
 		}
-		buf, err := argv.run(cve)
+		body, err := read("-")
 		if err != nil {
-			fmt.Fprintln(os.Stderr, "error:", err)
-			continue
-		}
-		if len(buf) > 0 {
-			fmt.Printf("%s", buf)
+			return body, err
 		}
+		return argv.cat("-", body)
 	}
 
-	if scanner.Err() != nil {
-		fmt.Fprintln(os.Stderr, "error:", scanner.Err())
-	}
-}
-
-func (argv *argvT) run(cve string) ([]byte, error) {
-	url, err := geturl(cve)
+	id, err := cveid.Parse(cve)
 	if err != nil {
 		if argv.verbose > 0 {
 			fmt.Fprintf(os.Stderr, "error: %s: %v: format is CVE-<YYYY>-<NNNN...>\n",
@@ -122,20 +207,42 @@ func (argv *argvT) run(cve string) ([]byte, error) {
 		}
 		return []byte{}, nil
 	}
-	if argv.verbose > 1 {
-		fmt.Fprintln(os.Stderr, url)
-	}
+
 	if argv.dryrun {
 		return []byte{}, nil
 	}
-	return argv.cat(url)
-}
 
-func (argv *argvT) cat(url string) ([]byte, error) {
-	body, err := read(url)
+	source, body, err := argv.fetch(id)
 	if err != nil {
 		return body, err
 	}
+	if argv.verbose > 1 {
+		fmt.Fprintln(os.Stderr, source)
+	}
+	return argv.cat(source, body)
+}
+
+// fetch resolves a CVE record from one source: the MITRE CVE Services API
+// when -key is set and -offline is not, since it can be ahead of the GitHub
+// mirror for freshly-published records; else the -repo mirror; else
+// raw.githubusercontent.com. A failed fetch does not fall back to the next
+// source. It returns the source the record was read from (an API URL, a
+// file path, or a URL) alongside the raw CNA JSON.
+func (argv *argvT) fetch(id cveid.ID) (string, []byte, error) {
+	if argv.mitre != nil && !argv.offline {
+		body, err := argv.mitre.Record(id.String())
+		return argv.mitre.BaseURL + "/cve/" + id.String(), body, err
+	}
+	if argv.mirror != nil {
+		body, err := argv.mirror.Read(id.YearString(), id.SeqString(), id.String())
+		return argv.mirror.Path(id.YearString(), id.SeqString(), id.String()), body, err
+	}
+	url := geturl(id)
+	body, err := read(url)
+	return url, body, err
+}
+
+func (argv *argvT) cat(source string, body []byte) ([]byte, error) {
 	if len(body) == 0 {
 		return body, nil
 	}
@@ -155,12 +262,30 @@ func (argv *argvT) cat(url string) ([]byte, error) {
 	if len(cve.Containers.Cna.Descriptions) == 0 {
 		return body, errNoDescr
 	}
+	if argv.format == "osv" {
+		entry, err := osv.FromCVE5(cve)
+		if err != nil {
+			return body, err
+		}
+		b, err := json.MarshalIndent(entry, "", "  ")
+		if err != nil {
+			return b, err
+		}
+		return append(b, '\n'), nil
+	}
 	data := &cvecat.Data{
 		CVE:     cve,
-		URL:     url,
+		URL:     source,
 		Version: config.Version(),
 	}
-	b, err := format(argv.format, data)
+	if argv.minScore > 0 || argv.minSeverity != "" {
+		score, ok := data.BestScore()
+		if !ok || score.BaseScore < argv.minScore ||
+			(argv.minSeverity != "" && !cvss.SeverityAtLeast(score.BaseSeverity, argv.minSeverity)) {
+			return []byte{}, nil
+		}
+	}
+	b, err := argv.render(data)
 	if err != nil {
 		return b, err
 	}
@@ -172,7 +297,7 @@ func read(url string) ([]byte, error) {
 		return io.ReadAll(os.Stdin)
 	}
 	// #nosec G107
-	resp, err := http.Get(url)
+	resp, err := httpClient.Get(url)
 	if err != nil {
 		return nil, err
 	}
@@ -191,111 +316,12 @@ func read(url string) ([]byte, error) {
 	return body, err
 }
 
-func geturl(id string) (string, error) {
-	if id == "-" {
-		return "-", nil
-	}
-	prefix, year, ref, err := parseID(id)
-	if err != nil {
-		return "", err
-	}
+func geturl(id cveid.ID) string {
+	ref := id.SeqString()
 	return fmt.Sprintf(
-		"https://raw.githubusercontent.com/CVEProject/cvelistV5/main/cves/%s/%sxxx/%s-%s-%s.json",
-		year,
+		"https://raw.githubusercontent.com/CVEProject/cvelistV5/main/cves/%s/%sxxx/%s.json",
+		id.YearString(),
 		ref[0:len(ref)-3],
-		prefix, year, ref,
-	), nil
-}
-
-func parseID(id string) (prefix, year, ref string, err error) {
-	prefix = "CVE"
-	p := strings.Split(id, "-")
-	switch len(p) {
-	case 1:
-		now := time.Now()
-		ref = p[0]
-		year = strconv.Itoa(now.Year())
-	case 2:
-		ref = p[1]
-		year = p[0]
-	case 3:
-		ref = p[2]
-		year = p[1]
-		prefix = strings.ToUpper(p[0])
-	default:
-		return prefix, year, ref, errInvalidCVE
-	}
-	if len(ref) < 4 {
-		ref = strings.Repeat("0", 4-len(ref)) + ref
-	}
-	if prefix != "CVE" {
-		return prefix, year, ref, errInvalidCVEPrefix
-	}
-	if ok, err := regexp.MatchString("^[0-9]{4}$", year); !ok || err != nil {
-		return prefix, year, ref, errInvalidCVEYear
-	}
-	if ok, err := regexp.MatchString(
-		"^[0-9][0-9][0-9][0-9]+$",
-		ref,
-	); !ok || err != nil {
-		return prefix, year, ref, errInvalidCVEID
-	}
-	return prefix, year, ref, nil
-}
-
-func join(elems []string, sep string) string {
-	return strings.Join(elems, sep)
-}
-
-var markdownEscaper = strings.NewReplacer(
-	// The backslash must be first.
-	`\`, `\\`,
-	`*`, `\*`,
-	`_`, `\_`,
-	`#`, `\#`,
-	"`", "\\`",
-	`[`, `\[`,
-	`]`, `\]`,
-	`(`, `\(`,
-	`)`, `\)`,
-	`>`, `\>`,
-	`+`, `\+`,
-	`-`, `\-`,
-	`.`, `\.`,
-	`!`, `\!`,
-	`|`, `\|`,
-	`~`, `\~`,
-)
-
-// mdescape takes a string and escapes special markdown characters.
-// Allows embedding text within a markdown document without its contents
-// being interpreted as markdown syntax.
-func mdescape(text string) string {
-	return markdownEscaper.Replace(text)
-}
-
-func format(f string, data *cvecat.Data) ([]byte, error) {
-	funcMap := template.FuncMap{
-		"replace": func(s, expr, repl string) string {
-			re, err := regexp.Compile(expr)
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "error: %v", err)
-				return s
-			}
-			return re.ReplaceAllString(s, repl)
-		},
-		"join":     join,
-		"mdescape": mdescape,
-	}
-
-	tmpl, err := template.New("format").Funcs(funcMap).Parse(f)
-	if err != nil {
-		return []byte{}, err
-	}
-
-	var buf bytes.Buffer
-	if err := tmpl.Execute(&buf, data); err != nil {
-		return buf.Bytes(), err
-	}
-	return buf.Bytes(), nil
+		id,
+	)
 }
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+)
+
+var errMitreKeyRequired = errors.New("-key (or CVE_API_KEY) is required for this command")
+
+var errMitreOffline = errors.New("-offline forbids network access to the MITRE CVE Services API")
+
+// mitreCommand drives cvecat's minimal CNA workflow on top of the MITRE CVE
+// Services API: list, reserve, quota and update. Responses are decoded and
+// run back through the -format template pipeline, the same as record
+// lookups, so e.g. reservation results can be formatted by the caller.
+func (argv *argvT) mitreCommand(cmd string, args []string) error {
+	if argv.mitre == nil {
+		return errMitreKeyRequired
+	}
+	if argv.offline {
+		return errMitreOffline
+	}
+
+	var body []byte
+	var err error
+	switch cmd {
+	case "list":
+		body, err = argv.mitre.List(url.Values{})
+	case "reserve":
+		q := url.Values{}
+		if len(args) > 0 {
+			q.Set("amount", args[0])
+		}
+		body, err = argv.mitre.Reserve(q)
+	case "quota":
+		body, err = argv.mitre.Quota()
+	case "update":
+		if len(args) < 1 {
+			return fmt.Errorf("update: usage: %s update <CVE-ID> [<json file>|-]", "cvecat")
+		}
+		src := "-"
+		if len(args) > 1 {
+			src = args[1]
+		}
+		var payload []byte
+		payload, err = readPayload(src)
+		if err != nil {
+			return err
+		}
+		body, err = argv.mitre.Update(args[0], payload)
+	}
+	if err != nil {
+		return err
+	}
+
+	b, err := argv.formatMitre(body)
+	if err != nil {
+		return err
+	}
+	if len(b) > 0 {
+		fmt.Printf("%s", b)
+	}
+	return nil
+}
+
+// readPayload reads an "update" request body from stdin ("-") or a local
+// JSON file. Unlike fetch(), which resolves CVE records over HTTP or from
+// a repo mirror, an update payload is always local: it's the caller's own
+// draft CNA container, not something cvecat fetches.
+func readPayload(src string) ([]byte, error) {
+	if src == "-" {
+		return io.ReadAll(os.Stdin)
+	}
+	return os.ReadFile(src)
+}
+
+// formatMitre runs a raw MITRE API response through -format. Unlike CVE
+// record lookups there is no cve5.CVE to unmarshal into, so the response is
+// decoded generically and handed to the template as-is.
+func (argv *argvT) formatMitre(body []byte) ([]byte, error) {
+	if argv.format == "json" || len(body) == 0 {
+		return body, nil
+	}
+	var data any
+	if err := json.Unmarshal(body, &data); err != nil {
+		return body, err
+	}
+	return argv.render(data)
+}
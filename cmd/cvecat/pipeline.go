@@ -0,0 +1,174 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// job is one line of pipeline input, tagged with its input position so
+// output can be reassembled in order.
+type job struct {
+	idx int
+	cve string
+}
+
+type result struct {
+	idx int
+	buf []byte
+	err error
+}
+
+// runPipeline reads CVE ids from r and fetches them with -j concurrent
+// workers, retrying transient errors (-retries) and rate-limited by
+// -rate, then writes results to stdout in input order regardless of which
+// worker finished first.
+func (argv *argvT) runPipeline(r io.Reader) {
+	jobs := make(chan job)
+	results := make(chan result)
+
+	go func() {
+		defer close(jobs)
+		scanner := bufio.NewScanner(r)
+		idx := 0
+		for scanner.Scan() {
+			cve := strings.TrimSpace(scanner.Text())
+			if cve == "" {
+				continue
+			}
+			jobs <- job{idx: idx, cve: cve}
+			idx++
+		}
+		if err := scanner.Err(); err != nil {
+			fmt.Fprintln(os.Stderr, "error:", err)
+		}
+	}()
+
+	workers := argv.concurrency
+	if workers < 1 {
+		workers = 1
+	}
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				buf, err := argv.fetchWithRetry(j.cve)
+				results <- result{idx: j.idx, buf: buf, err: err}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	argv.writeOrdered(results)
+}
+
+// writeOrdered drains results, printing each in input order as soon as its
+// turn comes up, buffering any that complete early.
+func (argv *argvT) writeOrdered(results <-chan result) {
+	pending := map[int]result{}
+	next := 0
+	for res := range results {
+		pending[res.idx] = res
+		for {
+			res, ok := pending[next]
+			if !ok {
+				break
+			}
+			if res.err != nil {
+				fmt.Fprintln(os.Stderr, "error:", res.err)
+			} else if len(res.buf) > 0 {
+				fmt.Printf("%s", res.buf)
+			}
+			delete(pending, next)
+			next++
+		}
+	}
+}
+
+// fetchWithRetry runs run(), retrying transient (network or 5xx) errors
+// with exponential backoff, and applies the -rate limiter before each
+// attempt.
+func (argv *argvT) fetchWithRetry(cve string) ([]byte, error) {
+	backoff := 500 * time.Millisecond
+	var buf []byte
+	var err error
+	for attempt := 0; attempt <= argv.retries; attempt++ {
+		argv.limiter.wait()
+		buf, err = argv.run(cve)
+		if err == nil || !isRetryable(err) {
+			return buf, err
+		}
+		if attempt == argv.retries {
+			break
+		}
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+	return buf, err
+}
+
+// mitre5xx matches a 5xx status error from the MITRE client, e.g.
+// "mitre: GET /cve/CVE-2023-1234: 503: ...".
+var mitre5xx = regexp.MustCompile(`^mitre: [A-Z]+ \S+: 5[0-9][0-9]: `)
+
+func isRetryable(err error) bool {
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+	msg := err.Error()
+	return (len(msg) >= 3 && msg[0] == '5' && isDigit(msg[1]) && isDigit(msg[2])) ||
+		mitre5xx.MatchString(msg)
+}
+
+func isDigit(b byte) bool {
+	return b >= '0' && b <= '9'
+}
+
+// tokenLimiter is a simple token-bucket rate limiter used to stay polite
+// to the fetch origin under -j concurrency.
+type tokenLimiter struct {
+	tokens chan struct{}
+}
+
+// newTokenLimiter returns a limiter allowing rps requests per second, or
+// nil (unlimited) when rps <= 0.
+func newTokenLimiter(rps int) *tokenLimiter {
+	if rps <= 0 {
+		return nil
+	}
+	l := &tokenLimiter{tokens: make(chan struct{}, rps)}
+	for i := 0; i < rps; i++ {
+		l.tokens <- struct{}{}
+	}
+	go func() {
+		ticker := time.NewTicker(time.Second / time.Duration(rps))
+		defer ticker.Stop()
+		for range ticker.C {
+			select {
+			case l.tokens <- struct{}{}:
+			default:
+			}
+		}
+	}()
+	return l
+}
+
+func (l *tokenLimiter) wait() {
+	if l == nil {
+		return
+	}
+	<-l.tokens
+}
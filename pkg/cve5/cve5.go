@@ -70,22 +70,7 @@ type CVE struct {
 			References []struct {
 				URL string `json:"url"`
 			} `json:"references"`
-			Metrics []struct {
-				CvssV30 struct {
-					Version               string  `json:"version"`
-					AttackComplexity      string  `json:"attackComplexity"`
-					AttackVector          string  `json:"attackVector"`
-					AvailabilityImpact    string  `json:"availabilityImpact"`
-					ConfidentialityImpact string  `json:"confidentialityImpact"`
-					IntegrityImpact       string  `json:"integrityImpact"`
-					PrivilegesRequired    string  `json:"privilegesRequired"`
-					Scope                 string  `json:"scope"`
-					UserInteraction       string  `json:"userInteraction"`
-					VectorString          string  `json:"vectorString"`
-					BaseScore             float64 `json:"baseScore"`
-					BaseSeverity          string  `json:"baseSeverity"`
-				} `json:"cvssV3_0"`
-			} `json:"metrics"`
+			Metrics []Metrics `json:"metrics"`
 			ProblemTypes []struct {
 				Descriptions []struct {
 					Type        string `json:"type"`
@@ -99,5 +84,45 @@ type CVE struct {
 				Discovery string `json:"discovery"`
 			} `json:"source"`
 		} `json:"cna"`
+		Adp []struct {
+			Metrics []Metrics `json:"metrics"`
+		} `json:"adp"`
 	} `json:"containers"`
 }
+
+// CvssV3Metric holds the CVSS v3.0/v3.1 sub-metrics, which share an
+// identical set of fields across both point releases.
+type CvssV3Metric struct {
+	Version               string  `json:"version"`
+	AttackComplexity      string  `json:"attackComplexity"`
+	AttackVector          string  `json:"attackVector"`
+	AvailabilityImpact    string  `json:"availabilityImpact"`
+	ConfidentialityImpact string  `json:"confidentialityImpact"`
+	IntegrityImpact       string  `json:"integrityImpact"`
+	PrivilegesRequired    string  `json:"privilegesRequired"`
+	Scope                 string  `json:"scope"`
+	UserInteraction       string  `json:"userInteraction"`
+	VectorString          string  `json:"vectorString"`
+	BaseScore             float64 `json:"baseScore"`
+	BaseSeverity          string  `json:"baseSeverity"`
+}
+
+// CvssV4Metric holds the CVSS v4.0 sub-metrics. v4.0 replaces several
+// v3.x fields (e.g. Scope) with new ones (e.g. AttackRequirements); cvecat
+// only needs the vector string and derived score, so those are the only
+// fields decoded here.
+type CvssV4Metric struct {
+	Version      string  `json:"version"`
+	VectorString string  `json:"vectorString"`
+	BaseScore    float64 `json:"baseScore"`
+	BaseSeverity string  `json:"baseSeverity"`
+}
+
+// Metrics is one element of a CVE record's "metrics" array. A record may
+// carry several of these, scored under different CVSS versions; at most
+// one of the fields below is populated in practice.
+type Metrics struct {
+	CvssV30 CvssV3Metric `json:"cvssV3_0"`
+	CvssV31 CvssV3Metric `json:"cvssV3_1"`
+	CvssV40 CvssV4Metric `json:"cvssV4_0"`
+}
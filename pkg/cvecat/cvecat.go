@@ -1,9 +1,59 @@
 package cvecat
 
-import "go.iscode.ca/cvecat/pkg/cve5"
+import (
+	"go.iscode.ca/cvecat/pkg/cve5"
+	"go.iscode.ca/cvecat/pkg/cvss"
+)
 
 type Data struct {
 	URL     string
 	Version string
 	CVE     cve5.CVE
 }
+
+// BestScore returns the CVSS metric parsed from the highest CVSS version
+// available across the CNA container and any ADP containers (v4.0 over
+// v3.1 over v3.0), parsed into a cvss.Score. ok is false if the record
+// carries no CVSS metric at all.
+func (d *Data) BestScore() (score cvss.Score, ok bool) {
+	var v30, v31, v40 string
+	collect(d.CVE.Containers.Cna.Metrics, &v30, &v31, &v40)
+	for _, adp := range d.CVE.Containers.Adp {
+		collect(adp.Metrics, &v30, &v31, &v40)
+	}
+
+	var v string
+	switch {
+	case v40 != "":
+		v = v40
+	case v31 != "":
+		v = v31
+	default:
+		v = v30
+	}
+	if v == "" {
+		return cvss.Score{}, false
+	}
+
+	s, err := cvss.Parse(v)
+	if err != nil {
+		return cvss.Score{}, false
+	}
+	return s, true
+}
+
+// collect scans a "metrics" array and records the last non-empty vector
+// string seen for each CVSS version into v30/v31/v40.
+func collect(metrics []cve5.Metrics, v30, v31, v40 *string) {
+	for _, m := range metrics {
+		if m.CvssV40.VectorString != "" {
+			*v40 = m.CvssV40.VectorString
+		}
+		if m.CvssV31.VectorString != "" {
+			*v31 = m.CvssV31.VectorString
+		}
+		if m.CvssV30.VectorString != "" {
+			*v30 = m.CvssV30.VectorString
+		}
+	}
+}
@@ -0,0 +1,147 @@
+// Package cveid provides a typed CVE identifier with a compact uint32
+// encoding, factored out of the ad hoc regex parsing that used to live in
+// cvecat's main.go.
+package cveid
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	yearBase = 2000
+	maxYear  = 2127
+	maxSeq   = 0x01ffffff
+)
+
+var (
+	ErrInvalidCVE       = errors.New("invalid CVE")
+	ErrInvalidCVEPrefix = errors.New("invalid CVE prefix")
+	ErrInvalidCVEYear   = errors.New("invalid CVE year")
+	ErrInvalidCVEID     = errors.New("invalid CVE identifier")
+	ErrYearRange        = fmt.Errorf("cveid: year must be in [%d, %d]", yearBase, maxYear)
+	ErrSeqRange         = fmt.Errorf("cveid: sequence must be in [0, %#x]", maxSeq)
+)
+
+// ID is a CVE identifier, packed as (year-2000)<<25 | seq so large CVE
+// lists can be deduped or sorted as plain uint32s instead of strings.
+type ID uint32
+
+// Encode packs a year and sequence number into an ID.
+func Encode(year uint16, seq uint32) (ID, error) {
+	if year < yearBase || year > maxYear {
+		return 0, ErrYearRange
+	}
+	if seq > maxSeq {
+		return 0, ErrSeqRange
+	}
+	return ID(uint32(year-yearBase)<<25 | seq), nil
+}
+
+// Year returns the CVE year, e.g. 2023.
+func (id ID) Year() uint16 {
+	return uint16(id>>25) + yearBase
+}
+
+// Seq returns the sequence number, e.g. 1234 for CVE-2023-1234.
+func (id ID) Seq() uint32 {
+	return uint32(id) & maxSeq
+}
+
+// String returns the canonical "CVE-YYYY-NNNN..." form.
+func (id ID) String() string {
+	return fmt.Sprintf("CVE-%d-%04d", id.Year(), id.Seq())
+}
+
+// YearString returns the CVE year as a decimal string.
+func (id ID) YearString() string {
+	return strconv.Itoa(int(id.Year()))
+}
+
+// SeqString returns the sequence number, zero-padded to at least 4
+// digits, as used in cvelistV5's "<ref-prefix>xxx" directory layout.
+func (id ID) SeqString() string {
+	return fmt.Sprintf("%04d", id.Seq())
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (id ID) MarshalText() ([]byte, error) {
+	return []byte(id.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (id *ID) UnmarshalText(b []byte) error {
+	parsed, err := Parse(string(b))
+	if err != nil {
+		return err
+	}
+	*id = parsed
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (id ID) MarshalJSON() ([]byte, error) {
+	return json.Marshal(id.String())
+}
+
+var (
+	yearRe = regexp.MustCompile(`^[0-9]{4}$`)
+	seqRe  = regexp.MustCompile(`^[0-9][0-9][0-9][0-9]+$`)
+)
+
+// Parse parses a CVE identifier, accepting the full "CVE-YYYY-N..." form,
+// a bare sequence number (the current year is inferred), and the legacy
+// lowercase "cve-yyyy-n..." form.
+func Parse(s string) (ID, error) {
+	if strings.TrimSpace(s) == "" {
+		return 0, ErrInvalidCVE
+	}
+
+	prefix := "CVE"
+	p := strings.Split(s, "-")
+
+	var year, seq string
+	switch len(p) {
+	case 1:
+		seq = p[0]
+		year = strconv.Itoa(time.Now().Year())
+	case 2:
+		year, seq = p[0], p[1]
+	case 3:
+		prefix = strings.ToUpper(p[0])
+		year, seq = p[1], p[2]
+	default:
+		return 0, ErrInvalidCVE
+	}
+	if seq == "" {
+		return 0, ErrInvalidCVEID
+	}
+	if len(seq) < 4 {
+		seq = strings.Repeat("0", 4-len(seq)) + seq
+	}
+
+	if prefix != "CVE" {
+		return 0, ErrInvalidCVEPrefix
+	}
+	if !yearRe.MatchString(year) {
+		return 0, ErrInvalidCVEYear
+	}
+	if !seqRe.MatchString(seq) {
+		return 0, ErrInvalidCVEID
+	}
+
+	y, err := strconv.Atoi(year)
+	if err != nil {
+		return 0, ErrInvalidCVEYear
+	}
+	n, err := strconv.Atoi(seq)
+	if err != nil {
+		return 0, ErrInvalidCVEID
+	}
+	return Encode(uint16(y), uint32(n))
+}
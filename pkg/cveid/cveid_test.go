@@ -0,0 +1,90 @@
+package cveid
+
+import "testing"
+
+func TestEncodeRoundTrip(t *testing.T) {
+	cases := []struct {
+		year uint16
+		seq  uint32
+	}{
+		{2000, 0},
+		{2000, 1},
+		{2023, 1234},
+		{2127, maxSeq},
+	}
+	for _, c := range cases {
+		id, err := Encode(c.year, c.seq)
+		if err != nil {
+			t.Fatalf("Encode(%d, %d): %v", c.year, c.seq, err)
+		}
+		if got := id.Year(); got != c.year {
+			t.Errorf("Encode(%d, %d).Year() = %d, want %d", c.year, c.seq, got, c.year)
+		}
+		if got := id.Seq(); got != c.seq {
+			t.Errorf("Encode(%d, %d).Seq() = %d, want %d", c.year, c.seq, got, c.seq)
+		}
+	}
+}
+
+func TestEncodeYearRange(t *testing.T) {
+	if _, err := Encode(1999, 1); err != ErrYearRange {
+		t.Errorf("Encode(1999, 1) error = %v, want %v", err, ErrYearRange)
+	}
+	if _, err := Encode(2128, 1); err != ErrYearRange {
+		t.Errorf("Encode(2128, 1) error = %v, want %v", err, ErrYearRange)
+	}
+}
+
+func TestEncodeSeqRange(t *testing.T) {
+	if _, err := Encode(2023, maxSeq+1); err != ErrSeqRange {
+		t.Errorf("Encode(2023, maxSeq+1) error = %v, want %v", err, ErrSeqRange)
+	}
+}
+
+func TestParse(t *testing.T) {
+	cases := []struct {
+		in   string
+		want string
+	}{
+		{"CVE-2023-1234", "CVE-2023-1234"},
+		{"cve-2023-1234", "CVE-2023-1234"},
+		{"2023-1234", "CVE-2023-1234"},
+		{"CVE-2023-123", "CVE-2023-0123"},
+		{"CVE-2000-1", "CVE-2000-0001"},
+	}
+	for _, c := range cases {
+		id, err := Parse(c.in)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", c.in, err)
+		}
+		if got := id.String(); got != c.want {
+			t.Errorf("Parse(%q).String() = %q, want %q", c.in, got, c.want)
+		}
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	cases := []string{
+		"",
+		"CVE-2023",
+		"BAD-2023-1234",
+		"CVE-20ab-1234",
+		"CVE-2023-1x",
+		"a-b-c-d",
+	}
+	for _, in := range cases {
+		if _, err := Parse(in); err == nil {
+			t.Errorf("Parse(%q) error = nil, want non-nil", in)
+		}
+	}
+}
+
+func TestSeqString(t *testing.T) {
+	id, err := Encode(2023, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := id.SeqString(); got != "0007" {
+		t.Errorf("SeqString() = %q, want %q", got, "0007")
+	}
+}
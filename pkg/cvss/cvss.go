@@ -0,0 +1,193 @@
+// Package cvss parses CVSS v2, v3.0, v3.1 and v4.0 vector strings into a
+// common Score, so templates and severity filters don't need to know
+// which CVSS version a given record carries.
+package cvss
+
+import (
+	"errors"
+	"math"
+	"strings"
+)
+
+var errEmptyVector = errors.New("cvss: empty vector")
+
+// Score is a parsed CVSS vector, normalized across versions.
+type Score struct {
+	Version      string
+	BaseScore    float64
+	BaseSeverity string
+	Vector       string
+	Metrics      map[string]string
+}
+
+// Parse parses a CVSS vector string, e.g.
+// "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H" or the unprefixed CVSS v2
+// form "AV:N/AC:L/Au:N/C:C/I:C/A:C".
+func Parse(vector string) (Score, error) {
+	vector = strings.TrimSpace(vector)
+	if vector == "" {
+		return Score{}, errEmptyVector
+	}
+
+	parts := strings.Split(vector, "/")
+	version := "2.0"
+	start := 0
+	if strings.HasPrefix(parts[0], "CVSS:") {
+		version = strings.TrimPrefix(parts[0], "CVSS:")
+		start = 1
+	}
+
+	metrics := make(map[string]string, len(parts)-start)
+	for _, p := range parts[start:] {
+		kv := strings.SplitN(p, ":", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		metrics[kv[0]] = kv[1]
+	}
+
+	var score float64
+	switch {
+	case strings.HasPrefix(version, "4"):
+		score = baseScoreV4(metrics)
+	case strings.HasPrefix(version, "3"):
+		score = baseScoreV3(metrics)
+	default:
+		version = "2.0"
+		score = baseScoreV2(metrics)
+	}
+
+	return Score{
+		Version:      version,
+		BaseScore:    score,
+		BaseSeverity: severity(score),
+		Vector:       vector,
+		Metrics:      metrics,
+	}, nil
+}
+
+var severityRank = map[string]int{"NONE": 0, "LOW": 1, "MEDIUM": 2, "HIGH": 3, "CRITICAL": 4}
+
+// SeverityAtLeast reports whether sev meets or exceeds min on the CVSS
+// qualitative scale (NONE < LOW < MEDIUM < HIGH < CRITICAL). Unknown
+// severities rank below NONE, so they never satisfy a minimum.
+func SeverityAtLeast(sev, min string) bool {
+	s, ok := severityRank[strings.ToUpper(sev)]
+	if !ok {
+		return false
+	}
+	return s >= severityRank[strings.ToUpper(min)]
+}
+
+// severity maps a 0-10 base score to the CVSS v3+ qualitative rating.
+// It is also used as an approximation for CVSS v2, which has no
+// standard rating scale of its own.
+func severity(score float64) string {
+	switch {
+	case score == 0:
+		return "NONE"
+	case score < 4:
+		return "LOW"
+	case score < 7:
+		return "MEDIUM"
+	case score < 9:
+		return "HIGH"
+	default:
+		return "CRITICAL"
+	}
+}
+
+// baseScoreV3 implements the CVSS v3.0/v3.1 base score formula. The two
+// versions share the same equations; both are rounded here with the v3.1
+// roundup function.
+func baseScoreV3(m map[string]string) float64 {
+	av := map[string]float64{"N": 0.85, "A": 0.62, "L": 0.55, "P": 0.2}[m["AV"]]
+	ac := map[string]float64{"L": 0.77, "H": 0.44}[m["AC"]]
+	ui := map[string]float64{"N": 0.85, "R": 0.62}[m["UI"]]
+	changed := m["S"] == "C"
+
+	var pr float64
+	if changed {
+		pr = map[string]float64{"N": 0.85, "L": 0.68, "H": 0.5}[m["PR"]]
+	} else {
+		pr = map[string]float64{"N": 0.85, "L": 0.62, "H": 0.27}[m["PR"]]
+	}
+
+	impactOf := map[string]float64{"H": 0.56, "L": 0.22, "N": 0}
+	c, i, a := impactOf[m["C"]], impactOf[m["I"]], impactOf[m["A"]]
+	iss := 1 - (1-c)*(1-i)*(1-a)
+
+	var impact float64
+	if changed {
+		impact = 7.52*(iss-0.029) - 3.25*math.Pow(iss-0.02, 15)
+	} else {
+		impact = 6.42 * iss
+	}
+	if impact <= 0 {
+		return 0
+	}
+
+	exploitability := 8.22 * av * ac * pr * ui
+
+	if changed {
+		return roundup(math.Min(1.08*(impact+exploitability), 10))
+	}
+	return roundup(math.Min(impact+exploitability, 10))
+}
+
+// roundup is the CVSS v3.1 "Roundup" function: round up to the nearest
+// 0.1, e.g. 4.02 -> 4.1.
+func roundup(x float64) float64 {
+	i := math.Round(x * 100000)
+	if math.Mod(i, 10000) == 0 {
+		return i / 100000
+	}
+	return (math.Floor(i/10000) + 1) / 10
+}
+
+// baseScoreV2 implements the CVSS v2 base score formula.
+func baseScoreV2(m map[string]string) float64 {
+	av := map[string]float64{"L": 0.395, "A": 0.646, "N": 1.0}[m["AV"]]
+	ac := map[string]float64{"H": 0.35, "M": 0.61, "L": 0.71}[m["AC"]]
+	au := map[string]float64{"M": 0.45, "S": 0.56, "N": 0.704}[m["Au"]]
+
+	impactOf := map[string]float64{"N": 0, "P": 0.275, "C": 0.660}
+	c, i, a := impactOf[m["C"]], impactOf[m["I"]], impactOf[m["A"]]
+	impact := 10.41 * (1 - (1-c)*(1-i)*(1-a))
+
+	exploitability := 20 * av * ac * au
+
+	fImpact := 1.176
+	if impact == 0 {
+		fImpact = 0
+	}
+
+	score := ((0.6 * impact) + (0.4 * exploitability) - 1.5) * fImpact
+	return math.Round(score*10) / 10
+}
+
+// baseScoreV4 approximates a CVSS v4.0 base score from its metrics. The
+// real v4.0 method scores via a MacroVector lookup table rather than a
+// closed-form formula; this weighted approximation is good enough for
+// triage-style severity filtering but is not spec-exact. The result is
+// capped at 10.
+func baseScoreV4(m map[string]string) float64 {
+	weights := map[string]map[string]float64{
+		"AV": {"N": 0.20, "A": 0.15, "L": 0.10, "P": 0.05},
+		"AC": {"L": 0.10, "H": 0.05},
+		"AT": {"N": 0.10, "P": 0.05},
+		"PR": {"N": 0.15, "L": 0.10, "H": 0.05},
+		"UI": {"N": 0.10, "P": 0.05, "A": 0},
+		"VC": {"H": 0.15, "L": 0.08, "N": 0},
+		"VI": {"H": 0.15, "L": 0.08, "N": 0},
+		"VA": {"H": 0.15, "L": 0.08, "N": 0},
+	}
+
+	var sum float64
+	for metric, levels := range weights {
+		if v, ok := m[metric]; ok {
+			sum += levels[v]
+		}
+	}
+	return math.Min(math.Round(sum*10*10)/10, 10)
+}
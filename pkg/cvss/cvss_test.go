@@ -0,0 +1,113 @@
+package cvss
+
+import "testing"
+
+func TestParseV31(t *testing.T) {
+	vector := "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H"
+	s, err := Parse(vector)
+	if err != nil {
+		t.Fatalf("Parse(%q): %v", vector, err)
+	}
+	if s.Version != "3.1" {
+		t.Errorf("Version = %q, want %q", s.Version, "3.1")
+	}
+	if s.BaseScore != 9.8 {
+		t.Errorf("BaseScore = %v, want %v", s.BaseScore, 9.8)
+	}
+	if s.BaseSeverity != "CRITICAL" {
+		t.Errorf("BaseSeverity = %q, want %q", s.BaseSeverity, "CRITICAL")
+	}
+}
+
+func TestParseV30(t *testing.T) {
+	vector := "CVSS:3.0/AV:N/AC:H/PR:N/UI:R/S:C/C:L/I:L/A:N"
+	s, err := Parse(vector)
+	if err != nil {
+		t.Fatalf("Parse(%q): %v", vector, err)
+	}
+	if s.Version != "3.0" {
+		t.Errorf("Version = %q, want %q", s.Version, "3.0")
+	}
+	if s.BaseSeverity != "MEDIUM" {
+		t.Errorf("BaseSeverity = %q, want %q", s.BaseSeverity, "MEDIUM")
+	}
+}
+
+func TestParseV2(t *testing.T) {
+	vector := "AV:N/AC:L/Au:N/C:C/I:C/A:C"
+	s, err := Parse(vector)
+	if err != nil {
+		t.Fatalf("Parse(%q): %v", vector, err)
+	}
+	if s.Version != "2.0" {
+		t.Errorf("Version = %q, want %q", s.Version, "2.0")
+	}
+	if s.BaseScore != 10.0 {
+		t.Errorf("BaseScore = %v, want %v", s.BaseScore, 10.0)
+	}
+}
+
+func TestParseV4(t *testing.T) {
+	vector := "CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:L/VI:L/VA:L"
+	s, err := Parse(vector)
+	if err != nil {
+		t.Fatalf("Parse(%q): %v", vector, err)
+	}
+	if s.Version != "4.0" {
+		t.Errorf("Version = %q, want %q", s.Version, "4.0")
+	}
+	if s.BaseScore != 8.9 {
+		t.Errorf("BaseScore = %v, want %v", s.BaseScore, 8.9)
+	}
+	if s.BaseSeverity != "HIGH" {
+		t.Errorf("BaseSeverity = %q, want %q", s.BaseSeverity, "HIGH")
+	}
+}
+
+func TestParseEmpty(t *testing.T) {
+	if _, err := Parse(""); err != errEmptyVector {
+		t.Errorf("Parse(\"\") error = %v, want %v", err, errEmptyVector)
+	}
+	if _, err := Parse("   "); err != errEmptyVector {
+		t.Errorf("Parse(whitespace) error = %v, want %v", err, errEmptyVector)
+	}
+}
+
+func TestSeverityThresholds(t *testing.T) {
+	cases := []struct {
+		score float64
+		want  string
+	}{
+		{0, "NONE"},
+		{3.9, "LOW"},
+		{4.0, "MEDIUM"},
+		{6.9, "MEDIUM"},
+		{7.0, "HIGH"},
+		{8.9, "HIGH"},
+		{9.0, "CRITICAL"},
+		{10.0, "CRITICAL"},
+	}
+	for _, c := range cases {
+		if got := severity(c.score); got != c.want {
+			t.Errorf("severity(%v) = %q, want %q", c.score, got, c.want)
+		}
+	}
+}
+
+func TestSeverityAtLeast(t *testing.T) {
+	cases := []struct {
+		sev, min string
+		want     bool
+	}{
+		{"HIGH", "MEDIUM", true},
+		{"MEDIUM", "HIGH", false},
+		{"critical", "high", true},
+		{"NONE", "LOW", false},
+		{"BOGUS", "LOW", false},
+	}
+	for _, c := range cases {
+		if got := SeverityAtLeast(c.sev, c.min); got != c.want {
+			t.Errorf("SeverityAtLeast(%q, %q) = %v, want %v", c.sev, c.min, got, c.want)
+		}
+	}
+}
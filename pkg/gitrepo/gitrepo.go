@@ -0,0 +1,94 @@
+// Package gitrepo mirrors a CVE records repository (cvelistV5) on local
+// disk so records can be resolved by walking a git working tree instead of
+// performing one HTTPS request per CVE.
+package gitrepo
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+// UpstreamURL is the canonical CVE Program repository mirrored by Repo.
+const UpstreamURL = "https://github.com/CVEProject/cvelistV5"
+
+// ErrOffline is returned when a Repo operation requires network access but
+// the repo was opened with Offline set.
+var ErrOffline = errors.New("gitrepo: offline: network access disabled")
+
+// Repo is a shallow, fast-forward-only clone of a CVE records repository.
+type Repo struct {
+	Dir     string
+	URL     string
+	Offline bool
+}
+
+// Open returns a Repo rooted at dir, shallow-cloning it from url on first
+// use. An empty url defaults to UpstreamURL.
+//
+// If dir is not yet a git working tree and Offline is true, Open fails
+// instead of cloning.
+func Open(dir, url string, offline bool) (*Repo, error) {
+	if url == "" {
+		url = UpstreamURL
+	}
+	r := &Repo{Dir: dir, URL: url, Offline: offline}
+
+	_, err := os.Stat(filepath.Join(dir, ".git"))
+	switch {
+	case err == nil:
+		return r, nil
+	case !os.IsNotExist(err):
+		return nil, err
+	case offline:
+		return nil, fmt.Errorf("gitrepo: %s: %w", dir, ErrOffline)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dir), 0o755); err != nil {
+		return nil, err
+	}
+	cmd := exec.Command("git", "clone", "--depth=1", r.URL, r.Dir)
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("gitrepo: clone %s: %w", r.URL, err)
+	}
+	return r, nil
+}
+
+// Update fetches and fast-forwards the mirror to the latest upstream commit.
+func (r *Repo) Update() error {
+	if r.Offline {
+		return fmt.Errorf("gitrepo: %s: %w", r.Dir, ErrOffline)
+	}
+	if err := r.git("fetch", "--depth=1", "origin"); err != nil {
+		return fmt.Errorf("gitrepo: fetch: %w", err)
+	}
+	if err := r.git("reset", "--hard", "origin/HEAD"); err != nil {
+		return fmt.Errorf("gitrepo: reset: %w", err)
+	}
+	return nil
+}
+
+// Path returns the on-disk path of a CVE record, following the cvelistV5
+// layout: cves/<year>/<ref-prefix>xxx/<id>.json.
+func (r *Repo) Path(year, ref, id string) string {
+	prefix := ref
+	if len(ref) > 3 {
+		prefix = ref[:len(ref)-3]
+	}
+	return filepath.Join(r.Dir, "cves", year, prefix+"xxx", id+".json")
+}
+
+// Read resolves and returns the raw CNA JSON for id (e.g. "CVE-2023-1234")
+// by reading it out of the working tree.
+func (r *Repo) Read(year, ref, id string) ([]byte, error) {
+	return os.ReadFile(r.Path(year, ref, id))
+}
+
+func (r *Repo) git(args ...string) error {
+	cmd := exec.Command("git", append([]string{"-C", r.Dir}, args...)...)
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
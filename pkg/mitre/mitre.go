@@ -0,0 +1,112 @@
+// Package mitre is a thin client for the MITRE CVE Services API
+// (https://cveawg.mitre.org), used to fetch records newer than the
+// cvelistV5 GitHub mirror and to drive a minimal CNA workflow: reserving
+// IDs, checking quota, listing assignments and publishing updates.
+package mitre
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// ProdURL and TestURL are the production and sandbox API endpoints.
+const (
+	ProdURL = "https://cveawg.mitre.org/api"
+	TestURL = "https://cveawg-test.mitre.org/api"
+)
+
+// Client calls the MITRE CVE Services API.
+type Client struct {
+	BaseURL    string
+	Key        string
+	User       string
+	Org        string
+	HTTPClient *http.Client
+}
+
+// NewClient returns a Client. test selects the cveawg-test.mitre.org
+// sandbox instead of the production API. httpClient is used to make
+// requests; if nil, http.DefaultClient is used.
+func NewClient(key, user, org string, test bool, httpClient *http.Client) *Client {
+	base := ProdURL
+	if test {
+		base = TestURL
+	}
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &Client{
+		BaseURL:    base,
+		Key:        key,
+		User:       user,
+		Org:        org,
+		HTTPClient: httpClient,
+	}
+}
+
+func (c *Client) do(method, path string, query url.Values, body []byte) ([]byte, error) {
+	u := c.BaseURL + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+
+	var reqBody io.Reader
+	if body != nil {
+		reqBody = bytes.NewReader(body)
+	}
+
+	req, err := http.NewRequest(method, u, reqBody)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("CVE-API-KEY", c.Key)
+	req.Header.Set("CVE-API-USER", c.User)
+	req.Header.Set("CVE-API-ORG", c.Org)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("mitre: %s %s: %d: %s", method, path, resp.StatusCode, respBody)
+	}
+	return respBody, nil
+}
+
+// Record fetches a single CVE record: GET /cve/{id}.
+func (c *Client) Record(id string) ([]byte, error) {
+	return c.do(http.MethodGet, "/cve/"+id, nil, nil)
+}
+
+// List returns CVE ID records assigned to the client's org: GET /cve-id.
+func (c *Client) List(query url.Values) ([]byte, error) {
+	return c.do(http.MethodGet, "/cve-id", query, nil)
+}
+
+// Reserve reserves one or more new CVE IDs: POST /cve-id.
+func (c *Client) Reserve(query url.Values) ([]byte, error) {
+	return c.do(http.MethodPost, "/cve-id", query, nil)
+}
+
+// Quota returns the client's org remaining CVE ID quota:
+// GET /org/{org}/id_quota.
+func (c *Client) Quota() ([]byte, error) {
+	return c.do(http.MethodGet, "/org/"+c.Org+"/id_quota", nil, nil)
+}
+
+// Update publishes a CNA container for id: PUT /cve/{id}/cna.
+func (c *Client) Update(id string, body []byte) ([]byte, error) {
+	return c.do(http.MethodPut, "/cve/"+id+"/cna", nil, body)
+}
@@ -0,0 +1,209 @@
+// Package osv converts CVE Services v5 (CNA JSON) records into OSV 1.x
+// schema entries, so cvecat output can be fed directly into OSV consumers.
+package osv
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"go.iscode.ca/cvecat/pkg/cve5"
+)
+
+// SchemaVersion is the OSV schema version produced by FromCVE5.
+const SchemaVersion = "1.6.0"
+
+var errNoCveID = errors.New("osv: missing CVE ID")
+
+// Entry is a (partial) OSV schema entry: https://ossf.github.io/osv-schema/
+type Entry struct {
+	SchemaVersion    string            `json:"schema_version,omitempty"`
+	ID               string            `json:"id"`
+	Aliases          []string          `json:"aliases,omitempty"`
+	Published        string            `json:"published,omitempty"`
+	Modified         string            `json:"modified,omitempty"`
+	Summary          string            `json:"summary,omitempty"`
+	Details          string            `json:"details,omitempty"`
+	Severity         []Severity        `json:"severity,omitempty"`
+	Affected         []Affected        `json:"affected,omitempty"`
+	References       []Reference       `json:"references,omitempty"`
+	DatabaseSpecific *DatabaseSpecific `json:"database_specific,omitempty"`
+}
+
+// Severity is an OSV severity entry.
+type Severity struct {
+	Type  string `json:"type"`
+	Score string `json:"score"`
+}
+
+// Reference is an OSV reference entry.
+type Reference struct {
+	Type string `json:"type"`
+	URL  string `json:"url"`
+}
+
+// DatabaseSpecific carries fields OSV does not standardize.
+type DatabaseSpecific struct {
+	CweIDs []string `json:"cwe_ids,omitempty"`
+}
+
+// Affected is an OSV affected-package entry.
+type Affected struct {
+	Package          *Package                  `json:"package,omitempty"`
+	Ranges           []Range                   `json:"ranges,omitempty"`
+	Versions         []string                  `json:"versions,omitempty"`
+	DatabaseSpecific *AffectedDatabaseSpecific `json:"database_specific,omitempty"`
+}
+
+// AffectedDatabaseSpecific carries the CVE5 defaultStatus for an Affected
+// entry that has no version ranges, so the affected/unaffected/unknown
+// status isn't silently lost when there's nothing else to convert.
+type AffectedDatabaseSpecific struct {
+	DefaultStatus string `json:"default_status,omitempty"`
+}
+
+// Package identifies the affected software.
+//
+// CVE5 Affected blocks carry a vendor/product pair rather than a package
+// ecosystem, so Ecosystem is left blank; callers that know the ecosystem
+// out-of-band can set it after conversion.
+type Package struct {
+	Ecosystem string `json:"ecosystem"`
+	Name      string `json:"name"`
+}
+
+// Range is an OSV version range.
+type Range struct {
+	Type   string  `json:"type"`
+	Events []Event `json:"events"`
+}
+
+// Event is a single point in a Range.
+type Event struct {
+	Introduced   string `json:"introduced,omitempty"`
+	Fixed        string `json:"fixed,omitempty"`
+	LastAffected string `json:"last_affected,omitempty"`
+}
+
+// FromCVE5 converts a parsed CVE5 record into an OSV entry.
+func FromCVE5(cve cve5.CVE) (Entry, error) {
+	id := cve.CveMetadata.CveID
+	if id == "" {
+		return Entry{}, errNoCveID
+	}
+
+	e := Entry{
+		SchemaVersion: SchemaVersion,
+		ID:            id,
+		Aliases:       []string{id},
+	}
+	if !cve.CveMetadata.DatePublished.IsZero() {
+		e.Published = cve.CveMetadata.DatePublished.Format(time.RFC3339)
+	}
+	if !cve.CveMetadata.DateUpdated.IsZero() {
+		e.Modified = cve.CveMetadata.DateUpdated.Format(time.RFC3339)
+	}
+
+	cna := cve.Containers.Cna
+
+	for _, d := range cna.Descriptions {
+		if !strings.HasPrefix(d.Lang, "en") {
+			continue
+		}
+		e.Summary = summarize(d.Value)
+		e.Details = d.Value
+		break
+	}
+
+	var cweIDs []string
+	for _, pt := range cna.ProblemTypes {
+		for _, d := range pt.Descriptions {
+			if d.CweID != "" {
+				cweIDs = append(cweIDs, d.CweID)
+			}
+		}
+	}
+	if len(cweIDs) > 0 {
+		e.DatabaseSpecific = &DatabaseSpecific{CweIDs: cweIDs}
+	}
+
+	for _, m := range cna.Metrics {
+		if v := m.CvssV30.VectorString; v != "" {
+			e.Severity = append(e.Severity, Severity{Type: "CVSS_V3", Score: v})
+		}
+		if v := m.CvssV31.VectorString; v != "" {
+			e.Severity = append(e.Severity, Severity{Type: "CVSS_V3", Score: v})
+		}
+		if v := m.CvssV40.VectorString; v != "" {
+			e.Severity = append(e.Severity, Severity{Type: "CVSS_V4", Score: v})
+		}
+	}
+
+	for _, ref := range cna.References {
+		e.References = append(e.References, Reference{Type: "WEB", URL: ref.URL})
+	}
+
+	for _, a := range cna.Affected {
+		// CVE5 records commonly omit versions[] and rely on defaultStatus
+		// ("affected", "unaffected" or "unknown") alone. An "unaffected"
+		// entry has nothing to report here; keep the rest so the status
+		// isn't silently lost.
+		if len(a.Versions) == 0 && a.DefaultStatus == "unaffected" {
+			continue
+		}
+
+		out := Affected{
+			Package: &Package{Name: packageName(a.Vendor, a.Product)},
+		}
+		if len(a.Versions) > 0 {
+			rangeType := "ECOSYSTEM"
+			var events []Event
+			for _, v := range a.Versions {
+				if v.VersionType == "semver" {
+					rangeType = "SEMVER"
+				}
+				introduced := v.Version
+				if introduced == "" {
+					introduced = "0"
+				}
+				events = append(events, Event{Introduced: introduced})
+				switch {
+				case v.LessThan != "":
+					events = append(events, Event{Fixed: v.LessThan})
+				case v.LessThanOrEqual != "":
+					events = append(events, Event{LastAffected: v.LessThanOrEqual})
+				}
+			}
+			out.Ranges = []Range{{Type: rangeType, Events: events}}
+		} else if a.DefaultStatus != "" {
+			out.DatabaseSpecific = &AffectedDatabaseSpecific{DefaultStatus: a.DefaultStatus}
+		}
+		e.Affected = append(e.Affected, out)
+	}
+
+	return e, nil
+}
+
+// summarize derives a one-line summary from the first line of a
+// (possibly multi-line) CNA description.
+func summarize(s string) string {
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		s = s[:i]
+	}
+	const max = 120
+	if len(s) > max {
+		s = strings.TrimSpace(s[:max]) + "..."
+	}
+	return s
+}
+
+func packageName(vendor, product string) string {
+	switch {
+	case vendor == "":
+		return product
+	case product == "":
+		return vendor
+	default:
+		return vendor + "/" + product
+	}
+}
@@ -0,0 +1,251 @@
+// Package report is a registry of built-in cvecat report templates
+// (markdown, plain, html, slack, jira). The markdown, plain and html
+// templates are modeled on the full-report style of tools like Vuls: a
+// header, severity, description, CWE table, affected-products table
+// grouped by vendor, and a references list. The slack and jira templates
+// carry a subset of these sections.
+package report
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+)
+
+// Names of the built-in templates, usable directly as -format values.
+const (
+	Markdown = "markdown"
+	Plain    = "plain"
+	HTML     = "html"
+	Slack    = "slack"
+	Jira     = "jira"
+)
+
+// Lookup returns the template source for a built-in name. HTML is looked
+// up the same way but must be parsed with html/template, not
+// text/template, so its escaping rules apply.
+func Lookup(name string) (string, bool) {
+	t, ok := templates[name]
+	return t, ok
+}
+
+// FileOverride reads dir/<name>.tmpl, if present, letting callers ship
+// org-specific templates that shadow (or add to) the built-in registry.
+// ok is false, with a nil error, when no such file exists.
+func FileOverride(dir, name string) (string, bool, error) {
+	if dir == "" {
+		return "", false, nil
+	}
+	b, err := os.ReadFile(dir + "/" + name + ".tmpl")
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", false, nil
+		}
+		return "", false, err
+	}
+	return string(b), true, nil
+}
+
+// Wrap wraps s to width columns, breaking only on word boundaries.
+func Wrap(width int, s string) string {
+	words := strings.Fields(s)
+	if len(words) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	lineLen := 0
+	for i, w := range words {
+		switch {
+		case i == 0:
+			// first word, nothing to do
+		case lineLen+1+len(w) > width:
+			b.WriteByte('\n')
+			lineLen = 0
+		default:
+			b.WriteByte(' ')
+			lineLen++
+		}
+		b.WriteString(w)
+		lineLen += len(w)
+	}
+	return b.String()
+}
+
+var severityColor = map[string]string{
+	"LOW":      "32",
+	"MEDIUM":   "33",
+	"HIGH":     "31",
+	"CRITICAL": "35",
+}
+
+// ColorSeverity wraps sev in an ANSI color code when stdout is a
+// terminal, and returns it unchanged otherwise (e.g. when piped).
+func ColorSeverity(sev string) string {
+	fi, err := os.Stdout.Stat()
+	if err != nil || fi.Mode()&os.ModeCharDevice == 0 {
+		return sev
+	}
+	code, ok := severityColor[strings.ToUpper(sev)]
+	if !ok {
+		return sev
+	}
+	return fmt.Sprintf("\x1b[%sm%s\x1b[0m", code, sev)
+}
+
+// GroupByVendor buckets a slice of CVE5 "affected" entries by their
+// Vendor field, returning the buckets as []any so callers don't need to
+// depend on cve5's anonymous struct type. Range over the result in a
+// template sorts by key, so output order is deterministic.
+func GroupByVendor(affected any) map[string][]any {
+	out := map[string][]any{}
+	rv := reflect.ValueOf(affected)
+	if rv.Kind() != reflect.Slice {
+		return out
+	}
+	for i := 0; i < rv.Len(); i++ {
+		item := rv.Index(i)
+		vendor := "unknown"
+		if f := item.FieldByName("Vendor"); f.IsValid() && f.Kind() == reflect.String && f.String() != "" {
+			vendor = f.String()
+		}
+		out[vendor] = append(out[vendor], item.Interface())
+	}
+	return out
+}
+
+const markdownTemplate = `# {{.CVE.CveMetadata.CveID}}
+
+**Published:** {{.CVE.CveMetadata.DatePublished.Format "2006-01-02"}} **Updated:** {{.CVE.CveMetadata.DateUpdated.Format "2006-01-02"}}
+**Severity:** {{colorSeverity (severity .)}} ({{with cvss .}}{{.Vector}}{{end}})
+
+{{wrap 78 (mdescape (index .CVE.Containers.Cna.Descriptions 0).Value)}}
+{{- $cwes := .CVE.Containers.Cna.ProblemTypes}}
+{{- if $cwes}}
+
+## CWE
+
+| ID | Description |
+| --- | --- |
+{{- range $cwes}}{{range .Descriptions}}
+| {{.CweID}} | {{mdescape .Description}} |
+{{- end}}{{end}}
+{{- end}}
+
+## Affected
+
+{{- range $vendor, $items := groupByVendor .CVE.Containers.Cna.Affected}}
+
+### {{mdescape $vendor}}
+
+| Product | Status |
+| --- | --- |
+{{- range $items}}
+| {{mdescape .Product}} | {{.DefaultStatus}} |
+{{- end}}
+{{- end}}
+
+## References
+{{- range .CVE.Containers.Cna.References}}
+- {{.URL}}
+{{- end}}
+`
+
+const plainTemplate = `{{.CVE.CveMetadata.CveID}}
+Published: {{.CVE.CveMetadata.DatePublished.Format "2006-01-02"}}  Updated: {{.CVE.CveMetadata.DateUpdated.Format "2006-01-02"}}
+Severity: {{colorSeverity (severity .)}} ({{with cvss .}}{{.Vector}}{{end}})
+
+{{wrap 78 (index .CVE.Containers.Cna.Descriptions 0).Value}}
+{{- $cwes := .CVE.Containers.Cna.ProblemTypes}}
+{{- if $cwes}}
+
+CWE:
+{{- range $cwes}}{{range .Descriptions}}
+  {{.CweID}}: {{.Description}}
+{{- end}}{{end}}
+{{- end}}
+
+Affected:
+{{- range $vendor, $items := groupByVendor .CVE.Containers.Cna.Affected}}
+  {{$vendor}}:
+  {{- range $items}}
+    {{.Product}} ({{.DefaultStatus}})
+  {{- end}}
+{{- end}}
+
+References:
+{{- range .CVE.Containers.Cna.References}}
+  {{.URL}}
+{{- end}}
+`
+
+const slackTemplate = `*{{.CVE.CveMetadata.CveID}}* _{{severity .}}_
+{{wrap 78 (index .CVE.Containers.Cna.Descriptions 0).Value}}
+{{- range .CVE.Containers.Cna.References}}
+<{{.URL}}|reference>
+{{- end}}
+`
+
+const jiraTemplate = `h2. {{.CVE.CveMetadata.CveID}}
+
+*Severity:* {{severity .}} ({{with cvss .}}{{.Vector}}{{end}})
+
+{{wrap 78 (index .CVE.Containers.Cna.Descriptions 0).Value}}
+
+h3. Affected
+{{- range $vendor, $items := groupByVendor .CVE.Containers.Cna.Affected}}
+* *{{$vendor}}*
+{{- range $items}}
+** {{.Product}} ({{.DefaultStatus}})
+{{- end}}
+{{- end}}
+
+h3. References
+{{- range .CVE.Containers.Cna.References}}
+* [{{.URL}}|{{.URL}}]
+{{- end}}
+`
+
+const htmlTemplate = `<article>
+<h1>{{.CVE.CveMetadata.CveID}}</h1>
+<p><strong>Published:</strong> {{.CVE.CveMetadata.DatePublished.Format "2006-01-02"}}
+<strong>Updated:</strong> {{.CVE.CveMetadata.DateUpdated.Format "2006-01-02"}}
+<strong>Severity:</strong> {{severity .}} ({{with cvss .}}{{.Vector}}{{end}})</p>
+<p>{{(index .CVE.Containers.Cna.Descriptions 0).Value}}</p>
+{{- $cwes := .CVE.Containers.Cna.ProblemTypes}}
+{{- if $cwes}}
+<h2>CWE</h2>
+<table>
+<tr><th>ID</th><th>Description</th></tr>
+{{- range $cwes}}{{range .Descriptions}}
+<tr><td>{{.CweID}}</td><td>{{.Description}}</td></tr>
+{{- end}}{{end}}
+</table>
+{{- end}}
+<h2>Affected</h2>
+{{- range $vendor, $items := groupByVendor .CVE.Containers.Cna.Affected}}
+<h3>{{$vendor}}</h3>
+<table>
+<tr><th>Product</th><th>Status</th></tr>
+{{- range $items}}
+<tr><td>{{.Product}}</td><td>{{.DefaultStatus}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+<h2>References</h2>
+<ul>
+{{- range .CVE.Containers.Cna.References}}
+<li><a href="{{.URL}}">{{.URL}}</a></li>
+{{- end}}
+</ul>
+</article>
+`
+
+var templates = map[string]string{
+	Markdown: markdownTemplate,
+	Plain:    plainTemplate,
+	HTML:     htmlTemplate,
+	Slack:    slackTemplate,
+	Jira:     jiraTemplate,
+}